@@ -1,489 +1,303 @@
 package main
 
 import (
-	"bufio"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/werbes/MktWireguardExporter/render"
+	"github.com/werbes/MktWireguardExporter/routeros"
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+	"github.com/werbes/MktWireguardExporter/wgshow"
 )
 
 // Program purpose:
-// - Read wg.rsc (MikroTik RouterOS export) to extract WireGuard peer entries.
+// - Read WireGuard peer entries off a MikroTik RouterOS device, either from a
+//   wg.rsc export (-source=file, the default) or live from the device's API
+//   (-source=api).
 // - Read wg.conf (a known-good client config) to obtain constant values used for all clients
 //   like ListenPort and the server's PublicKey.
-// - Generate one Windows .cmd script per peer named after the peer IP (client-address without /32),
-//   which builds a .conf with echo lines, moves it into WireGuard's folder, and installs it as a service.
+// - Generate one client bundle per peer, in the formats selected by -formats
+//   (cmd, conf, linux, mac, qr), named after the peer IP, written to out/.
+// - Optionally (-diff) compare against a snapshot from the previous run and only
+//   emit bundles for peers that are new or changed.
+// - Optionally (-reconcile) compare wg.rsc against a running server's
+//   `wg show <iface> dump`, flagging peers never handshaken, peers missing
+//   from wg.rsc, and peers stale beyond -stale.
 
 func main() {
 	cwd, _ := os.Getwd()
 
-	// Input files at project root by default
-	rscPath := filepath.Join(cwd, "wg.rsc")
-	confPath := filepath.Join(cwd, "wg.conf")
+	source := flag.String("source", "file", "where to read peers from: file (wg.rsc export) or api (live RouterOS API)")
+	rscFlag := flag.String("rsc", filepath.Join(cwd, "wg.rsc"), "path to the wg.rsc export (-source=file)")
+	confFlag := flag.String("conf", filepath.Join(cwd, "wg.conf"), "path to the known-good client wg.conf")
+	router := flag.String("router", "", "RouterOS host[:port] (-source=api)")
+	user := flag.String("user", "", "RouterOS API username (-source=api)")
+	password := flag.String("password", "", "RouterOS API password (-source=api)")
+	useTLS := flag.Bool("tls", false, "connect to the RouterOS API over TLS (-source=api), verifying the device's certificate against the system root pool")
+	insecureSkipVerify := flag.Bool("insecure-skip-verify", false, "skip RouterOS certificate verification (-tls); needed for self-signed RouterOS certs")
+	diff := flag.Bool("diff", false, "only emit bundles for peers new or changed since the last run")
+	snapshotFlag := flag.String("snapshot", filepath.Join(cwd, "wg.snapshot.json"), "snapshot file used by -diff")
+	formatsFlag := flag.String("formats", "cmd", "comma-separated artifacts to generate: cmd, conf, linux, mac, qr")
+	outFlag := flag.String("out", filepath.Join(cwd, "out"), "directory bundles are written to")
+	iface := flag.String("iface", "wg0", "WireGuard interface name used by the linux/mac install scripts")
+	strict := flag.Bool("strict", false, "fail the run if any peer fails validation, instead of skipping it")
+	reportFlag := flag.String("report", filepath.Join(cwd, "wg.report.json"), "path to write the accepted/rejected peer report")
+	reconcile := flag.Bool("reconcile", false, "compare wg.rsc against a running server's `wg show <iface> dump` and write a reconcile report")
+	dumpFlag := flag.String("dump", "", "path to captured `wg show <iface> dump` output (-reconcile); empty shells out live")
+	staleFlag := flag.Duration("stale", 72*time.Hour, "-reconcile: how long since a peer's last handshake before it's flagged stale")
+	flag.Parse()
+
+	renderers, err := render.Resolve(splitCSVFlag(*formatsFlag))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in -formats: %v\n", err)
+		os.Exit(1)
+	}
 
-	base, err := parseBaseConf(confPath)
+	base, err := parseBaseConf(*confFlag)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing wg.conf: %v\n", err)
 		os.Exit(1)
 	}
 
-	peers, err := parseRouterOSPeers(rscPath)
+	report, err := fetchPeersReport(*source, *rscFlag, routeros.DialOptions{
+		Address:            *router,
+		User:               *user,
+		Password:           *password,
+		TLS:                *useTLS,
+		InsecureSkipVerify: *insecureSkipVerify,
+		Timeout:            10 * time.Second,
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing wg.rsc: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error fetching peers: %v\n", err)
 		os.Exit(1)
 	}
+	if err := writeReport(*reportFlag, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report %s: %v\n", *reportFlag, err)
+		os.Exit(1)
+	}
+	if len(report.Rejected) > 0 {
+		fmt.Fprintf(os.Stderr, "%d peer(s) rejected; see %s\n", len(report.Rejected), *reportFlag)
+		if *strict {
+			os.Exit(1)
+		}
+	}
+	peers := report.Accepted
 
 	if len(peers) == 0 {
-		fmt.Fprintln(os.Stderr, "No peers found in wg.rsc (/interface wireguard peers)")
+		fmt.Fprintln(os.Stderr, "No WireGuard peers found")
 		os.Exit(1)
 	}
 
-	// Generate one .cmd per peer
-	for _, p := range peers {
-		if p.ClientAddress == "" || p.PrivateKey == "" || p.PresharedKey == "" || p.EndpointAddress == "" || p.EndpointPort == "" {
-			// Skip incomplete entries
-			continue
+	toRender := peers
+	if *diff {
+		prev, err := routeros.LoadSnapshot(*snapshotFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading snapshot %s: %v\n", *snapshotFlag, err)
+			os.Exit(1)
+		}
+		added, changed := routeros.Diff(prev, peers)
+		toRender = append(added, changed...)
+		fmt.Printf("Diff: %d new, %d changed, %d unchanged\n", len(added), len(changed), len(peers)-len(toRender))
+		if err := routeros.SaveSnapshot(*snapshotFlag, peers); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving snapshot %s: %v\n", *snapshotFlag, err)
+			os.Exit(1)
 		}
-		ip := ipOnly(p.ClientAddress) // strip /CIDR
-		if ip == "" {
+	}
+
+	if err := os.MkdirAll(*outFlag, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *outFlag, err)
+		os.Exit(1)
+	}
+
+	for _, p := range toRender {
+		if !p.IsComplete() {
+			// Skip incomplete entries
 			continue
 		}
+		ip := p.ClientAddress.Addr().String()
 
-		// Build AllowedIPs: prefer RouterOS allowed-address; reorder so /32 is last
+		// Build AllowedIPs: prefer RouterOS allowed-address; reorder so /32 (or /128) is last
 		allowed := p.AllowedAddress
-		if allowed == "" {
+		if len(allowed) == 0 {
 			// Fallback: try to compose from wg.conf AllowedIPs by replacing client /32 if present
-			if base.AllowedIPs != "" {
-				// If base contains any /32, replace with this client's /32
-				parts := splitCSV(base.AllowedIPs)
-				for i := range parts {
-					if strings.HasSuffix(parts[i], "/32") {
-						parts[i] = p.ClientAddress
+			if len(base.AllowedIPs) > 0 {
+				allowed = make([]netip.Prefix, len(base.AllowedIPs))
+				copy(allowed, base.AllowedIPs)
+				for i, a := range allowed {
+					if a.Bits() == a.Addr().BitLen() {
+						allowed[i] = p.ClientAddress
 					}
 				}
-				allowed = strings.Join(parts, ",")
 			} else {
-				allowed = p.ClientAddress
+				allowed = []netip.Prefix{p.ClientAddress}
 			}
 		}
 		allowed = reorderAllowedIPs(allowed)
 
-		script, err := renderCmd(ip, cmdData{
+		data := render.Data{
+			Interface:    *iface,
 			ListenPort:   base.ListenPort,
 			PrivateKey:   p.PrivateKey,
 			Address:      p.ClientAddress,
-			DNS:          coalesce(p.ClientDNS, base.DNS),
+			DNS:          coalesceDNS(p.ClientDNS, base.DNS),
 			PublicKey:    base.ServerPublicKey, // server's public key used in [Peer]
 			AllowedIPs:   allowed,
 			PresharedKey: p.PresharedKey,
-			Endpoint:     fmt.Sprintf("%s:%s", p.EndpointAddress, p.EndpointPort),
-		})
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", ip, err)
-			continue
+			Endpoint:     p.Endpoint,
 		}
 
-		outPath := filepath.Join(cwd, ip+".que")
-		if err := os.WriteFile(outPath, []byte(script), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
-			continue
-		}
-		fmt.Printf("Wrote %s\n", outPath)
-	}
-}
-
-// BaseConf holds constants from wg.conf
-type BaseConf struct {
-	ListenPort      string
-	ServerPublicKey string // [Peer] PublicKey (the server's)
-	DNS             string // optional fallback if RouterOS lacks client-dns
-	AllowedIPs      string // optional template
-}
-
-func parseBaseConf(path string) (BaseConf, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return BaseConf{}, err
-	}
-	defer f.Close()
-
-	var b BaseConf
-	s := bufio.NewScanner(f)
-	inPeer := false
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
-			continue
-		}
-		if strings.HasPrefix(line, "[") {
-			inPeer = strings.EqualFold(line, "[Peer]")
-			continue
-		}
-		key, val, ok := splitKV(line)
-		if !ok {
-			continue
-		}
-		switch strings.ToLower(key) {
-		case "listenport":
-			b.ListenPort = val
-		case "dns":
-			b.DNS = val
-		case "publickey":
-			if inPeer {
-				b.ServerPublicKey = val
+		for _, r := range renderers {
+			artifact, err := r.Render(ip, data)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping %s (%s): %v\n", ip, r.Format(), err)
+				continue
 			}
-		case "allowedips":
-			if inPeer {
-				b.AllowedIPs = val
+			outPath := filepath.Join(*outFlag, r.FileName(ip))
+			if err := os.WriteFile(outPath, artifact, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
+				continue
 			}
+			fmt.Printf("Wrote %s\n", outPath)
 		}
 	}
-	if err := s.Err(); err != nil {
-		return BaseConf{}, err
-	}
-	if b.ServerPublicKey == "" {
-		return b, errors.New("wg.conf missing [Peer] PublicKey (server public key)")
-	}
-	// ListenPort is optional for clients but include if present
-	return b, nil
-}
-
-// RouterOS peer entry from wg.rsc
-type Peer struct {
-	Name            string
-	ClientAddress   string
-	ClientDNS       string
-	EndpointAddress string
-	EndpointPort    string
-	PrivateKey      string
-	PublicKey       string
-	PresharedKey    string
-	AllowedAddress  string
-}
 
-func parseRouterOSPeers(path string) ([]Peer, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	lines := splitLines(string(data))
-	// First, join backslash-continued lines
-	joined := joinContinuations(lines)
-
-	// Find the peers section
-	inPeers := false
-	var peers []Peer
-	for i := 0; i < len(joined); i++ {
-		line := strings.TrimSpace(joined[i])
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		if strings.HasPrefix(line, "/interface wireguard peers") {
-			inPeers = true
-			continue
-		}
-		if strings.HasPrefix(line, "/") { // another section starts
-			inPeers = false
-		}
-		if !inPeers {
-			continue
-		}
-		if strings.HasPrefix(line, "add ") {
-			p := parsePeerAddLine(line)
-			if p != nil {
-				peers = append(peers, *p)
+	if *reconcile {
+		var dump []wgshow.Peer
+		var err error
+		if *dumpFlag != "" {
+			f, ferr := os.Open(*dumpFlag)
+			if ferr != nil {
+				fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", *dumpFlag, ferr)
+				os.Exit(1)
 			}
+			_, dump, err = wgshow.Parse(f)
+			f.Close()
+		} else {
+			_, dump, err = wgshow.Dump(*iface)
 		}
-	}
-	return peers, nil
-}
-
-func parsePeerAddLine(line string) *Peer {
-	// remove leading 'add '
-	rest := strings.TrimSpace(strings.TrimPrefix(line, "add "))
-	if rest == "" {
-		return nil
-	}
-	// Split into tokens by spaces, but honor quotes
-	tokens := splitFieldsPreserveQuotes(rest)
-	if len(tokens) == 0 {
-		return nil
-	}
-
-	// Combine tokens robustly:
-	// Treat only tokens of the form key=... (where key is [A-Za-z0-9-]+ and token does not start with '"')
-	// as the start of a field. If the value part after '=' is empty, absorb subsequent tokens as the value
-	// until the next key-token appears. This handles both quoted (may contain '=') and unquoted continuations.
-	var combined []string
-
-	isKeyToken := func(tok string) bool {
-		if strings.HasPrefix(tok, "\"") {
-			return false
-		}
-		eq := strings.IndexByte(tok, '=')
-		if eq <= 0 {
-			return false
-		}
-		left := tok[:eq]
-		for i := 0; i < len(left); i++ {
-			c := left[i]
-			if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-') {
-				return false
-			}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading wg show dump: %v\n", err)
+			os.Exit(1)
 		}
-		return true
-	}
 
-	for i := 0; i < len(tokens); i++ {
-		t := tokens[i]
-		if !isKeyToken(t) {
-			// not a key token; skip (it will be absorbed by a preceding key if appropriate)
-			continue
-		}
-		eq := strings.IndexByte(t, '=')
-		key := t[:eq]
-		val := t[eq+1:]
-		if val == "" {
-			// absorb following tokens until next key token
-			for i+1 < len(tokens) && !isKeyToken(tokens[i+1]) {
-				i++
-				next := strings.TrimSpace(tokens[i])
-				if val != "" {
-					val += " "
-				}
-				val += next
-			}
+		rec := wgshow.Reconcile(peers, dump, *staleFlag)
+		reconcilePath := filepath.Join(*outFlag, "wg.reconcile.json")
+		if err := writeJSONFile(reconcilePath, rec); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", reconcilePath, err)
+			os.Exit(1)
 		}
-		combined = append(combined, key+"="+val)
+		fmt.Printf("Reconcile: %d never handshaken, %d missing from wg.rsc, %d stale (see %s)\n",
+			len(rec.NeverHandshaken), len(rec.MissingFromRSC), len(rec.Stale), reconcilePath)
 	}
+}
 
-	kv := map[string]string{}
-	for _, t := range combined {
-		if eq := strings.IndexByte(t, '='); eq > 0 {
-			k := t[:eq]
-			v := t[eq+1:]
-			v = trimQuotes(v)
-			kv[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+// fetchPeersReport reads peers from wg.rsc or the live RouterOS API,
+// depending on source, validating every one.
+func fetchPeersReport(source, rscPath string, dial routeros.DialOptions) (*routeros.Report, error) {
+	switch source {
+	case "file":
+		return routeros.ParseFileReport(rscPath)
+	case "api":
+		if dial.Address == "" {
+			return nil, errors.New("-source=api requires -router")
 		}
-	}
-
-	p := &Peer{
-		Name:            kv["name"],
-		ClientAddress:   firstNonEmpty(kv["client-address"], kv["address"], kv["clientaddress"]),
-		ClientDNS:       firstNonEmpty(kv["client-dns"], kv["dns"], kv["clientdns"]),
-		EndpointAddress: firstNonEmpty(kv["endpoint-address"], kv["endpoint"], kv["endpointaddress"]),
-		EndpointPort:    firstNonEmpty(kv["endpoint-port"], kv["endpointport"]),
-		PrivateKey:      kv["private-key"],
-		PublicKey:       kv["public-key"],
-		PresharedKey:    kv["preshared-key"],
-		AllowedAddress:  firstNonEmpty(kv["allowed-address"], kv["allowedaddress"]),
-	}
-	// Some exports might use responder=yes with no private-key (server side only) â€” skip those
-	if p.ClientAddress == "" || p.PrivateKey == "" || p.PresharedKey == "" {
-		// Debug: show which keys were parsed for this line
-		var keys []string
-		for k := range kv {
-			keys = append(keys, k)
+		client, err := routeros.Dial(dial)
+		if err != nil {
+			return nil, err
 		}
-		sort.Strings(keys)
-		fmt.Fprintf(os.Stderr, "DEBUG skip: have-keys=%v client-address='%s' priv?%v psk?%v\n", keys, p.ClientAddress, p.PrivateKey != "", p.PresharedKey != "")
-		fmt.Fprintf(os.Stderr, "DEBUG tokens=%v\n", tokens)
-		fmt.Fprintf(os.Stderr, "DEBUG combined=%v\n", combined)
-		return nil
+		defer client.Close()
+		return client.FetchPeersReport()
+	default:
+		return nil, fmt.Errorf("unknown -source %q (want file or api)", source)
 	}
-	return p
 }
 
-// Rendering
-
-type cmdData struct {
-	ListenPort   string
-	PrivateKey   string
-	Address      string
-	DNS          string
-	PublicKey    string
-	AllowedIPs   string
-	PresharedKey string
-	Endpoint     string
+// writeReport marshals the accepted/rejected peer report as JSON so CI
+// pipelines regenerating configs can check it. The report carries no
+// key material: accepted peers are reduced to routeros.AcceptedSummary
+// so a report meant to be archived by CI doesn't become another place
+// a client's private/preshared key is stored.
+func writeReport(path string, report *routeros.Report) error {
+	return writeJSONFile(path, report.Summary())
 }
 
-func renderCmd(ip string, d cmdData) (string, error) {
-	if ip == "" {
-		return "", errors.New("empty ip")
+// writeJSONFile marshals v as indented JSON and writes it to path.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
 	}
-	conf := fmt.Sprintf("%s.conf", ip)
-	appendOp := ">>" // use append after the first line
+	return os.WriteFile(path, data, 0644)
+}
 
-	var b strings.Builder
-	// Ensure CRLF endings for Windows .cmd readability
-	crlf := "\r\n"
+// BaseConf holds the constants from wg.conf that apply to every client.
+type BaseConf struct {
+	ListenPort      uint16
+	ServerPublicKey wgcfg.Key      // [Peer] PublicKey (the server's)
+	DNS             []string       // optional fallback if RouterOS lacks client-dns
+	AllowedIPs      []netip.Prefix // optional template
+}
 
-	// Start building the .cmd content
-	b.WriteString(fmt.Sprintf("echo [Interface] > %s%s", conf, crlf))
-	if d.ListenPort != "" {
-		b.WriteString(fmt.Sprintf("echo ListenPort = %s %s %s%s", d.ListenPort, appendOp, conf, crlf))
-	}
-	if d.PrivateKey != "" {
-		b.WriteString(fmt.Sprintf("echo PrivateKey = %s %s %s%s", d.PrivateKey, appendOp, conf, crlf))
-	}
-	if d.Address != "" {
-		b.WriteString(fmt.Sprintf("echo Address = %s %s %s%s", d.Address, appendOp, conf, crlf))
-	}
-	if d.DNS != "" {
-		b.WriteString(fmt.Sprintf("echo DNS = %s %s %s%s", d.DNS, appendOp, conf, crlf))
+func parseBaseConf(path string) (BaseConf, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return BaseConf{}, err
 	}
-	// Blank line
-	b.WriteString(fmt.Sprintf("echo. %s %s%s", appendOp, conf, crlf))
 
-	b.WriteString(fmt.Sprintf("echo [Peer] %s %s%s", appendOp, conf, crlf))
-	if d.PublicKey != "" {
-		b.WriteString(fmt.Sprintf("echo PublicKey = %s %s %s%s", d.PublicKey, appendOp, conf, crlf))
-	}
-	if d.AllowedIPs != "" {
-		b.WriteString(fmt.Sprintf("echo AllowedIPs = %s %s %s%s", d.AllowedIPs, appendOp, conf, crlf))
-	}
-	if d.PresharedKey != "" {
-		b.WriteString(fmt.Sprintf("echo PresharedKey = %s %s %s%s", d.PresharedKey, appendOp, conf, crlf))
+	cfg, err := wgcfg.Parse(data)
+	if err != nil {
+		return BaseConf{}, fmt.Errorf("parsing wg.conf: %w", err)
 	}
-	if d.Endpoint != "" {
-		b.WriteString(fmt.Sprintf("echo Endpoint = %s %s %s%s", d.Endpoint, appendOp, conf, crlf))
+	if len(cfg.Peers) == 0 || cfg.Peers[0].PublicKey.IsZero() {
+		return BaseConf{}, errors.New("wg.conf missing [Peer] PublicKey (server public key)")
 	}
 
-	// Move and install service
-	b.WriteString(fmt.Sprintf("move /y %s \"c:\\program files\\wireguard\\\"%s", conf, crlf))
-	b.WriteString(fmt.Sprintf("\"C:\\Program Files\\WireGuard\\wireguard.exe\" /installtunnelservice \"c:\\program files\\wireguard\\%s\"%s", conf, crlf))
-
-	return b.String(), nil
+	return BaseConf{
+		ListenPort:      cfg.Interface.ListenPort,
+		ServerPublicKey: cfg.Peers[0].PublicKey,
+		DNS:             cfg.Interface.DNS,
+		AllowedIPs:      cfg.Peers[0].AllowedIPs,
+	}, nil
 }
 
 // Helpers
 
-func splitKV(line string) (key, val string, ok bool) {
-	if i := strings.Index(line, "="); i > -1 {
-		key = strings.TrimSpace(line[:i])
-		val = strings.TrimSpace(line[i+1:])
-		return key, val, true
-	}
-	return "", "", false
-}
-
-func splitLines(s string) []string {
-	s = strings.ReplaceAll(s, "\r\n", "\n")
-	s = strings.ReplaceAll(s, "\r", "\n")
-	return strings.Split(s, "\n")
-}
-
-func joinContinuations(lines []string) []string {
-	var out []string
-	var buf strings.Builder
-	for _, raw := range lines {
-		l := strings.TrimRight(raw, " \t")
-		if strings.HasSuffix(l, "\\") {
-			// remove trailing backslash; keep a space between joined parts
-			l = strings.TrimSuffix(l, "\\")
-			buf.WriteString(strings.TrimRight(l, " "))
-			buf.WriteByte(' ')
-			continue
-		}
-		if buf.Len() > 0 {
-			buf.WriteString(strings.TrimSpace(l))
-			out = append(out, buf.String())
-			buf.Reset()
-		} else {
-			out = append(out, raw)
-		}
-	}
-	// flush if any
-	if buf.Len() > 0 {
-		out = append(out, buf.String())
-	}
-	return out
-}
-
-func splitFieldsPreserveQuotes(s string) []string {
+func splitCSVFlag(s string) []string {
 	var out []string
-	var cur strings.Builder
-	inQuote := false
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		switch c {
-		case '"':
-			inQuote = !inQuote
-			cur.WriteByte(c)
-		case ' ':
-			if inQuote {
-				cur.WriteByte(c)
-			} else if cur.Len() > 0 {
-				out = append(out, cur.String())
-				cur.Reset()
-			}
-		default:
-			cur.WriteByte(c)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
 		}
 	}
-	if cur.Len() > 0 {
-		out = append(out, cur.String())
-	}
 	return out
 }
 
-func trimQuotes(s string) string {
-	s = strings.TrimSpace(s)
-	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
-		return s[1 : len(s)-1]
-	}
-	return s
-}
-
-func ipOnly(addr string) string {
-	if i := strings.Index(addr, "/"); i > 0 {
-		return addr[:i]
-	}
-	return addr
-}
-
-func splitCSV(s string) []string {
-	parts := strings.Split(s, ",")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
-	}
-	return parts
-}
-
-func reorderAllowedIPs(s string) string {
-	parts := splitCSV(s)
-	// Move /32 to the end and sort others lexicographically stable
-	var cidr32 []string
-	var others []string
-	for _, p := range parts {
-		if strings.HasSuffix(p, "/32") {
-			cidr32 = append(cidr32, p)
-		} else if p != "" {
+func reorderAllowedIPs(prefixes []netip.Prefix) []netip.Prefix {
+	// Move host routes (/32, /128) to the end; sort the rest lexicographically.
+	var host, others []netip.Prefix
+	for _, p := range prefixes {
+		if p.Bits() == p.Addr().BitLen() {
+			host = append(host, p)
+		} else {
 			others = append(others, p)
 		}
 	}
-	sort.Strings(others)
-	res := append(others, cidr32...)
-	return strings.Join(res, ",")
+	sort.Slice(others, func(i, j int) bool { return others[i].String() < others[j].String() })
+	return append(others, host...)
 }
 
-func coalesce(vals ...string) string {
-	for _, v := range vals {
-		if strings.TrimSpace(v) != "" {
-			return v
-		}
-	}
-	return ""
-}
-
-func firstNonEmpty(vals ...string) string {
-	for _, v := range vals {
-		if strings.TrimSpace(v) != "" {
-			return v
-		}
+func coalesceDNS(primary, fallback []string) []string {
+	if len(primary) > 0 {
+		return primary
 	}
-	return ""
+	return fallback
 }
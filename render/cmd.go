@@ -0,0 +1,64 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cmdRenderer builds a Windows .cmd script that writes a .conf via
+// `echo` lines, moves it into WireGuard's install folder, and
+// registers it as a tunnel service.
+type cmdRenderer struct{}
+
+func (cmdRenderer) Format() string { return "cmd" }
+
+func (cmdRenderer) FileName(ip string) string { return ip + ".cmd" }
+
+func (cmdRenderer) Render(ip string, d Data) ([]byte, error) {
+	if ip == "" {
+		return nil, errors.New("empty ip")
+	}
+	conf := fmt.Sprintf("%s.conf", ip)
+	appendOp := ">>" // use append after the first line
+
+	var b []byte
+	crlf := "\r\n" // ensure CRLF endings for Windows .cmd readability
+	write := func(format string, args ...any) {
+		b = append(b, []byte(fmt.Sprintf(format, args...))...)
+	}
+
+	write("echo [Interface] > %s%s", conf, crlf)
+	if d.ListenPort != 0 {
+		write("echo ListenPort = %d %s %s%s", d.ListenPort, appendOp, conf, crlf)
+	}
+	if !d.PrivateKey.IsZero() {
+		write("echo PrivateKey = %s %s %s%s", d.PrivateKey, appendOp, conf, crlf)
+	}
+	if d.Address.IsValid() {
+		write("echo Address = %s %s %s%s", d.Address, appendOp, conf, crlf)
+	}
+	if len(d.DNS) > 0 {
+		write("echo DNS = %s %s %s%s", strings.Join(d.DNS, ","), appendOp, conf, crlf)
+	}
+	write("echo. %s %s%s", appendOp, conf, crlf) // blank line
+
+	write("echo [Peer] %s %s%s", appendOp, conf, crlf)
+	if !d.PublicKey.IsZero() {
+		write("echo PublicKey = %s %s %s%s", d.PublicKey, appendOp, conf, crlf)
+	}
+	if len(d.AllowedIPs) > 0 {
+		write("echo AllowedIPs = %s %s %s%s", joinPrefixes(d.AllowedIPs), appendOp, conf, crlf)
+	}
+	if !d.PresharedKey.IsZero() {
+		write("echo PresharedKey = %s %s %s%s", d.PresharedKey, appendOp, conf, crlf)
+	}
+	if !d.Endpoint.IsZero() {
+		write("echo Endpoint = %s %s %s%s", d.Endpoint, appendOp, conf, crlf)
+	}
+
+	write("move /y %s \"c:\\program files\\wireguard\\\"%s", conf, crlf)
+	write("\"C:\\Program Files\\WireGuard\\wireguard.exe\" /installtunnelservice \"c:\\program files\\wireguard\\%s\"%s", conf, crlf)
+
+	return b, nil
+}
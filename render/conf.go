@@ -0,0 +1,14 @@
+package render
+
+import "github.com/werbes/MktWireguardExporter/wgcfg"
+
+// confRenderer builds a raw wg-quick .conf suitable for `wg-quick up`.
+type confRenderer struct{}
+
+func (confRenderer) Format() string { return "conf" }
+
+func (confRenderer) FileName(ip string) string { return ip + ".conf" }
+
+func (confRenderer) Render(_ string, d Data) ([]byte, error) {
+	return wgcfg.Marshal(d.toConfig())
+}
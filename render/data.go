@@ -0,0 +1,46 @@
+// Package render turns a single client's WireGuard parameters into the
+// artifacts an operator hands to that client: a Windows install script,
+// a raw wg-quick conf, a Linux/macOS install script, or a QR code for
+// the mobile apps. Each artifact kind is a Renderer; main selects which
+// ones to produce via -formats.
+package render
+
+import (
+	"net/netip"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// Data is everything a Renderer needs to build one client's bundle.
+type Data struct {
+	Interface    string // WireGuard interface name for scripted installs, e.g. "wg0"
+	ListenPort   uint16
+	PrivateKey   wgcfg.Key
+	Address      netip.Prefix
+	DNS          []string
+	PublicKey    wgcfg.Key // server's public key
+	AllowedIPs   []netip.Prefix
+	PresharedKey wgcfg.Key
+	Endpoint     wgcfg.Endpoint
+}
+
+// toConfig builds the wgcfg.Config this client's conf file represents.
+func (d Data) toConfig() *wgcfg.Config {
+	cfg := &wgcfg.Config{
+		Interface: wgcfg.Interface{
+			PrivateKey: d.PrivateKey,
+			ListenPort: d.ListenPort,
+			DNS:        d.DNS,
+		},
+		Peers: []wgcfg.Peer{{
+			PublicKey:    d.PublicKey,
+			PresharedKey: d.PresharedKey,
+			AllowedIPs:   d.AllowedIPs,
+			Endpoint:     d.Endpoint,
+		}},
+	}
+	if d.Address.IsValid() {
+		cfg.Interface.Address = []netip.Prefix{d.Address}
+	}
+	return cfg
+}
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// linuxRenderer builds a shell script that installs the client's conf
+// under /etc/wireguard and brings the tunnel up via wg-quick/systemd.
+type linuxRenderer struct{}
+
+func (linuxRenderer) Format() string { return "linux" }
+
+func (linuxRenderer) FileName(ip string) string { return ip + "-linux-install.sh" }
+
+func (linuxRenderer) Render(_ string, d Data) ([]byte, error) {
+	iface := d.Interface
+	if iface == "" {
+		iface = "wg0"
+	}
+	conf, err := wgcfg.Marshal(d.toConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = append(b, []byte("#!/bin/sh\nset -e\n\n")...)
+	b = append(b, []byte(fmt.Sprintf("cat > /etc/wireguard/%s.conf <<'EOF'\n", iface))...)
+	b = append(b, conf...)
+	b = append(b, []byte("EOF\nchmod 600 "+fmt.Sprintf("/etc/wireguard/%s.conf\n", iface))...)
+	b = append(b, []byte(fmt.Sprintf("systemctl enable --now wg-quick@%s\n", iface))...)
+	return b, nil
+}
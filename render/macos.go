@@ -0,0 +1,36 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// macRenderer builds a shell script that installs the client's conf
+// for the `wireguard-tools` Homebrew formula and brings the tunnel up.
+type macRenderer struct{}
+
+func (macRenderer) Format() string { return "mac" }
+
+func (macRenderer) FileName(ip string) string { return ip + "-macos-install.sh" }
+
+func (macRenderer) Render(_ string, d Data) ([]byte, error) {
+	iface := d.Interface
+	if iface == "" {
+		iface = "wg0"
+	}
+	conf, err := wgcfg.Marshal(d.toConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = append(b, []byte("#!/bin/sh\nset -e\n\n")...)
+	b = append(b, []byte("command -v wg-quick >/dev/null 2>&1 || brew install wireguard-tools\n\n")...)
+	b = append(b, []byte(fmt.Sprintf("sudo mkdir -p /usr/local/etc/wireguard\nsudo tee /usr/local/etc/wireguard/%s.conf >/dev/null <<'EOF'\n", iface))...)
+	b = append(b, conf...)
+	b = append(b, []byte("EOF\n")...)
+	b = append(b, []byte(fmt.Sprintf("sudo chmod 600 /usr/local/etc/wireguard/%s.conf\n", iface))...)
+	b = append(b, []byte(fmt.Sprintf("sudo wg-quick up %s\n", iface))...)
+	return b, nil
+}
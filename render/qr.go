@@ -0,0 +1,22 @@
+package render
+
+import (
+	"github.com/skip2/go-qrcode"
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// qrRenderer encodes the client's conf as a PNG QR code for the
+// WireGuard mobile apps to import by camera.
+type qrRenderer struct{}
+
+func (qrRenderer) Format() string { return "qr" }
+
+func (qrRenderer) FileName(ip string) string { return ip + ".png" }
+
+func (qrRenderer) Render(_ string, d Data) ([]byte, error) {
+	conf, err := wgcfg.Marshal(d.toConfig())
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.Encode(string(conf), qrcode.Medium, 256)
+}
@@ -0,0 +1,37 @@
+package render
+
+import "fmt"
+
+// Renderer produces one client-bundle artifact for a single peer.
+type Renderer interface {
+	// Format is the renderer's -formats key, e.g. "conf" or "qr".
+	Format() string
+	// FileName is the name (relative to the output directory) the
+	// rendered artifact should be written under for the peer at ip.
+	FileName(ip string) string
+	// Render produces the artifact's bytes.
+	Render(ip string, d Data) ([]byte, error)
+}
+
+// byFormat maps every supported -formats key to its Renderer.
+var byFormat = map[string]Renderer{
+	cmdRenderer{}.Format():   cmdRenderer{},
+	confRenderer{}.Format():  confRenderer{},
+	linuxRenderer{}.Format(): linuxRenderer{},
+	macRenderer{}.Format():   macRenderer{},
+	qrRenderer{}.Format():    qrRenderer{},
+}
+
+// Resolve looks up the Renderer for each requested -formats key,
+// erroring on the first unknown one.
+func Resolve(formats []string) ([]Renderer, error) {
+	var out []Renderer
+	for _, f := range formats {
+		r, ok := byFormat[f]
+		if !ok {
+			return nil, fmt.Errorf("unknown format %q (want one of cmd, conf, linux, mac, qr)", f)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
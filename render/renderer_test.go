@@ -0,0 +1,181 @@
+package render
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+func testData(t *testing.T) Data {
+	t.Helper()
+	priv, err := wgcfg.ParseKey("YAtZI0VVD1hzIpDzfHuwnaGTzhA90WtAl6fQCk9GVUM=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	pub, err := wgcfg.ParseKey("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	psk, err := wgcfg.ParseKey("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAI=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	return Data{
+		Interface:    "wg0",
+		ListenPort:   51820,
+		PrivateKey:   priv,
+		Address:      netip.MustParsePrefix("10.0.0.2/32"),
+		DNS:          []string{"1.1.1.1"},
+		PublicKey:    pub,
+		AllowedIPs:   []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")},
+		PresharedKey: psk,
+		Endpoint:     wgcfg.Endpoint{Host: "vpn.example.com", Port: 51820},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		want    []string
+		wantErr bool
+	}{
+		{"all known formats", []string{"cmd", "conf", "linux", "mac", "qr"}, []string{"cmd", "conf", "linux", "mac", "qr"}, false},
+		{"unknown format", []string{"bogus"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderers, err := Resolve(tt.formats)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%v) error = %v, wantErr %v", tt.formats, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(renderers) != len(tt.want) {
+				t.Fatalf("len(renderers) = %d, want %d", len(renderers), len(tt.want))
+			}
+			for i, r := range renderers {
+				if r.Format() != tt.want[i] {
+					t.Errorf("renderers[%d].Format() = %q, want %q", i, r.Format(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConfRendererRender(t *testing.T) {
+	d := testData(t)
+	out, err := confRenderer{}.Render("10.0.0.2", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	cfg, err := wgcfg.Parse(out)
+	if err != nil {
+		t.Fatalf("rendered conf didn't parse back: %v\n%s", err, out)
+	}
+	if cfg.Interface.ListenPort != d.ListenPort {
+		t.Errorf("ListenPort = %d, want %d", cfg.Interface.ListenPort, d.ListenPort)
+	}
+	if len(cfg.Peers) != 1 || cfg.Peers[0].Endpoint != d.Endpoint {
+		t.Errorf("Peers = %v, want one peer with Endpoint %v", cfg.Peers, d.Endpoint)
+	}
+}
+
+func TestConfRendererFileName(t *testing.T) {
+	if got := (confRenderer{}).FileName("10.0.0.2"); got != "10.0.0.2.conf" {
+		t.Errorf("FileName = %q, want %q", got, "10.0.0.2.conf")
+	}
+}
+
+func TestLinuxRendererRender(t *testing.T) {
+	d := testData(t)
+	out, err := linuxRenderer{}.Render("10.0.0.2", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{
+		"#!/bin/sh",
+		"cat > /etc/wireguard/wg0.conf <<'EOF'",
+		"chmod 600 /etc/wireguard/wg0.conf",
+		"systemctl enable --now wg-quick@wg0",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("linux install script missing %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestMacRendererRenderDoesNotPipeCatIntoTee(t *testing.T) {
+	d := testData(t)
+	out, err := macRenderer{}.Render("10.0.0.2", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	s := string(out)
+	if strings.Contains(s, "cat |") {
+		t.Errorf("macOS install script pipes cat into tee, which hangs reading the script's own stdin:\n%s", s)
+	}
+	for _, want := range []string{
+		"#!/bin/sh",
+		"sudo tee /usr/local/etc/wireguard/wg0.conf >/dev/null <<'EOF'",
+		"sudo wg-quick up wg0",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("macOS install script missing %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestCmdRendererRender(t *testing.T) {
+	d := testData(t)
+	out, err := cmdRenderer{}.Render("10.0.0.2", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{
+		"echo [Interface] > 10.0.0.2.conf\r\n",
+		"echo PrivateKey = " + d.PrivateKey.String(),
+		"echo [Peer] >> 10.0.0.2.conf\r\n",
+		`/installtunnelservice "c:\program files\wireguard\10.0.0.2.conf"`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("cmd install script missing %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestCmdRendererRejectsEmptyIP(t *testing.T) {
+	d := testData(t)
+	if _, err := (cmdRenderer{}).Render("", d); err == nil {
+		t.Fatal("Render with an empty ip: got nil error, want one")
+	}
+}
+
+func TestQRRendererRender(t *testing.T) {
+	d := testData(t)
+	out, err := qrRenderer{}.Render("10.0.0.2", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(out) == 0 {
+		t.Fatal("Render produced no bytes")
+	}
+	// PNG signature.
+	if len(out) < 8 || out[0] != 0x89 || out[1] != 'P' || out[2] != 'N' || out[3] != 'G' {
+		t.Fatalf("Render did not produce a PNG (bad signature)")
+	}
+}
+
+func TestJoinPrefixes(t *testing.T) {
+	prefixes := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24"), netip.MustParsePrefix("fd00::/64")}
+	got := joinPrefixes(prefixes)
+	want := "10.0.0.0/24,fd00::/64"
+	if got != want {
+		t.Errorf("joinPrefixes = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+package render
+
+import (
+	"net/netip"
+	"strings"
+)
+
+func joinPrefixes(prefixes []netip.Prefix) string {
+	parts := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, ",")
+}
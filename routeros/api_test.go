@@ -0,0 +1,64 @@
+package routeros
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestWriteReadLengthRoundTrip(t *testing.T) {
+	lengths := []int{0, 1, 0x7f, 0x80, 0x3fff, 0x4000, 0x1fffff, 0x200000, 0xfffffff, 0x10000000, 1 << 20}
+	for _, l := range lengths {
+		var buf bytes.Buffer
+		if err := writeLength(&buf, l); err != nil {
+			t.Fatalf("writeLength(%d): %v", l, err)
+		}
+		got, err := readLength(bufio.NewReader(&buf))
+		if err != nil {
+			t.Fatalf("readLength after writeLength(%d): %v", l, err)
+		}
+		if got != l {
+			t.Errorf("round-trip of %d produced %d", l, got)
+		}
+	}
+}
+
+func TestReadLengthInvalidPrefix(t *testing.T) {
+	// 0xF8 isn't a valid first byte under RouterOS's length encoding
+	// (0x80/0xC0/0xE0/0xF0 are the only valid high-bit patterns).
+	_, err := readLength(bufio.NewReader(bytes.NewReader([]byte{0xF8})))
+	if err == nil {
+		t.Fatal("readLength with an invalid prefix byte: got nil error, want one")
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	got := attrs([]string{"=name=alice", "=client-address=10.0.0.2/32", "noequals"})
+	want := map[string]string{"name": "alice", "client-address": "10.0.0.2/32"}
+	if len(got) != len(want) {
+		t.Fatalf("attrs = %#v, want %#v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("attrs[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestTraps(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{"message attribute wins", []string{"=message=invalid user name or password", "=category=1"}, "invalid user name or password"},
+		{"no message attribute falls back to the raw words", []string{"=category=1"}, "=category=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := traps(tt.in); got != tt.want {
+				t.Errorf("traps(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
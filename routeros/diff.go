@@ -0,0 +1,56 @@
+package routeros
+
+// Diff compares a previous snapshot of peers against the current set
+// and reports which are new and which changed, keyed by PublicKey (or
+// Name, for peers whose public key RouterOS didn't record). Unchanged
+// peers are omitted so a caller can regenerate bundles only for what
+// actually needs it.
+func Diff(prev, cur []Peer) (added, changed []Peer) {
+	prevByKey := make(map[string]Peer, len(prev))
+	for _, p := range prev {
+		prevByKey[peerKey(p)] = p
+	}
+
+	for _, p := range cur {
+		old, ok := prevByKey[peerKey(p)]
+		if !ok {
+			added = append(added, p)
+			continue
+		}
+		if !peersEqual(old, p) {
+			changed = append(changed, p)
+		}
+	}
+	return added, changed
+}
+
+func peerKey(p Peer) string {
+	if !p.PublicKey.IsZero() {
+		return "pub:" + p.PublicKey.String()
+	}
+	return "name:" + p.Name
+}
+
+func peersEqual(a, b Peer) bool {
+	if a.ClientAddress != b.ClientAddress ||
+		a.Endpoint != b.Endpoint ||
+		a.PrivateKey != b.PrivateKey ||
+		a.PublicKey != b.PublicKey ||
+		a.PresharedKey != b.PresharedKey {
+		return false
+	}
+	if len(a.ClientDNS) != len(b.ClientDNS) || len(a.AllowedAddress) != len(b.AllowedAddress) {
+		return false
+	}
+	for i := range a.ClientDNS {
+		if a.ClientDNS[i] != b.ClientDNS[i] {
+			return false
+		}
+	}
+	for i := range a.AllowedAddress {
+		if a.AllowedAddress[i] != b.AllowedAddress[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package routeros
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+func mustTestKey(t *testing.T, s string) wgcfg.Key {
+	t.Helper()
+	k, err := wgcfg.ParseKey(s)
+	if err != nil {
+		t.Fatalf("ParseKey(%q): %v", s, err)
+	}
+	return k
+}
+
+func mustTestPrefix(t *testing.T, s string) netip.Prefix {
+	t.Helper()
+	return netip.MustParsePrefix(s)
+}
+
+func TestDiff(t *testing.T) {
+	unchanged := Peer{Name: "alice", PublicKey: mustTestKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")}
+	changedOld := Peer{Name: "bob", PublicKey: mustTestKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAI="), ClientAddress: mustTestPrefix(t, "10.0.0.2/32")}
+	changedNew := changedOld
+	changedNew.ClientAddress = mustTestPrefix(t, "10.0.0.3/32")
+	noPubKeyOld := Peer{Name: "carol"}
+	noPubKeyNew := Peer{Name: "carol", ClientDNS: []string{"1.1.1.1"}}
+	added := Peer{Name: "dave", PublicKey: mustTestKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM=")}
+
+	prev := []Peer{unchanged, changedOld, noPubKeyOld}
+	cur := []Peer{unchanged, changedNew, noPubKeyNew, added}
+
+	gotAdded, gotChanged := Diff(prev, cur)
+
+	if len(gotAdded) != 1 || gotAdded[0].Name != "dave" {
+		t.Errorf("added = %v, want [dave]", gotAdded)
+	}
+	if len(gotChanged) != 2 {
+		t.Fatalf("changed = %v, want 2 entries", gotChanged)
+	}
+	names := map[string]bool{gotChanged[0].Name: true, gotChanged[1].Name: true}
+	if !names["bob"] || !names["carol"] {
+		t.Errorf("changed = %v, want bob and carol", gotChanged)
+	}
+}
+
+func TestPeerKeyFallsBackToNameWhenPublicKeyMissing(t *testing.T) {
+	p := Peer{Name: "carol"}
+	if got, want := peerKey(p), "name:carol"; got != want {
+		t.Errorf("peerKey(%v) = %q, want %q", p, got, want)
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	peers := []Peer{{Name: "alice", PublicKey: mustTestKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")}}
+	added, changed := Diff(peers, peers)
+	if len(added) != 0 || len(changed) != 0 {
+		t.Errorf("Diff of identical slices: added = %v, changed = %v, want none", added, changed)
+	}
+}
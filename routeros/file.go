@@ -0,0 +1,223 @@
+package routeros
+
+import (
+	"os"
+	"strings"
+)
+
+// ParseFile reads a RouterOS `/interface wireguard peers export`
+// (conventionally named wg.rsc) and returns the peers it defines,
+// silently discarding rows that fail validation. Use ParseFileReport
+// to also see what was rejected and why.
+func ParseFile(path string) ([]Peer, error) {
+	report, err := ParseFileReport(path)
+	if err != nil {
+		return nil, err
+	}
+	return report.Accepted, nil
+}
+
+// ParseFileReport reads a wg.rsc export and validates every peer row,
+// returning both the accepted peers and the rejected ones with the
+// *wgcfg.ParseError(s) that explain why each was rejected.
+func ParseFileReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	joined, lineNos := joinContinuations(splitLines(string(data)))
+
+	inPeers := false
+	report := &Report{}
+	for i, raw := range joined {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "/interface wireguard peers") {
+			inPeers = true
+			continue
+		}
+		if strings.HasPrefix(line, "/") { // another section starts
+			inPeers = false
+		}
+		if !inPeers {
+			continue
+		}
+		if strings.HasPrefix(line, "add ") {
+			kv, name := parsePeerAddLine(line)
+			p, errs := peerFromFields(kv, lineNos[i])
+			report.add(p, name, lineNos[i], errs)
+		}
+	}
+	return report, nil
+}
+
+// parsePeerAddLine tokenizes one `add ...` line from the peers section
+// into a flat key/value map using RouterOS's own property names.
+func parsePeerAddLine(line string) (kv map[string]string, name string) {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "add "))
+	if rest == "" {
+		return nil, ""
+	}
+	// Split into tokens by spaces, but honor quotes
+	tokens := splitFieldsPreserveQuotes(rest)
+	if len(tokens) == 0 {
+		return nil, ""
+	}
+
+	// Combine tokens robustly:
+	// Treat only tokens of the form key=... (where key is [A-Za-z0-9-]+ and token does not start with '"')
+	// as the start of a field. If the value part after '=' is empty, absorb subsequent tokens as the value
+	// until the next key-token appears. This handles both quoted (may contain '=') and unquoted continuations.
+	var combined []string
+
+	isKeyToken := func(tok string) bool {
+		if strings.HasPrefix(tok, "\"") {
+			return false
+		}
+		eq := strings.IndexByte(tok, '=')
+		if eq <= 0 {
+			return false
+		}
+		left := tok[:eq]
+		for i := 0; i < len(left); i++ {
+			c := left[i]
+			if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-') {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if !isKeyToken(t) {
+			// not a key token; skip (it will be absorbed by a preceding key if appropriate)
+			continue
+		}
+		eq := strings.IndexByte(t, '=')
+		key := t[:eq]
+		val := t[eq+1:]
+		if val == "" {
+			// absorb following tokens until next key token
+			for i+1 < len(tokens) && !isKeyToken(tokens[i+1]) {
+				i++
+				next := strings.TrimSpace(tokens[i])
+				if val != "" {
+					val += " "
+				}
+				val += next
+			}
+		}
+		combined = append(combined, key+"="+val)
+	}
+
+	kv = map[string]string{}
+	for _, t := range combined {
+		if eq := strings.IndexByte(t, '='); eq > 0 {
+			k := t[:eq]
+			v := t[eq+1:]
+			v = trimQuotes(v)
+			kv[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+		}
+	}
+	// The script form uses RouterOS aliases for a couple of properties
+	// that the API reports under their canonical names.
+	if v, ok := kv["clientaddress"]; ok {
+		kv["client-address"] = v
+	}
+	if v, ok := kv["clientdns"]; ok {
+		kv["client-dns"] = v
+	}
+	if v, ok := kv["endpointaddress"]; ok {
+		kv["endpoint-address"] = v
+	}
+	if v, ok := kv["endpointport"]; ok {
+		kv["endpoint-port"] = v
+	}
+	if v, ok := kv["allowedaddress"]; ok {
+		kv["allowed-address"] = v
+	}
+
+	return kv, kv["name"]
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.Split(s, "\n")
+}
+
+// joinContinuations joins backslash-continued lines into single
+// entries and returns, alongside each entry, the 1-based line number
+// it started on.
+func joinContinuations(lines []string) ([]string, []int) {
+	var out []string
+	var lineNos []int
+	var buf strings.Builder
+	startLine := 0
+	for i, raw := range lines {
+		l := strings.TrimRight(raw, " \t")
+		if strings.HasSuffix(l, "\\") {
+			if buf.Len() == 0 {
+				startLine = i + 1
+			}
+			// remove trailing backslash; keep a space between joined parts
+			l = strings.TrimSuffix(l, "\\")
+			buf.WriteString(strings.TrimRight(l, " "))
+			buf.WriteByte(' ')
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString(strings.TrimSpace(l))
+			out = append(out, buf.String())
+			lineNos = append(lineNos, startLine)
+			buf.Reset()
+		} else {
+			out = append(out, raw)
+			lineNos = append(lineNos, i+1)
+		}
+	}
+	// flush if any
+	if buf.Len() > 0 {
+		out = append(out, buf.String())
+		lineNos = append(lineNos, startLine)
+	}
+	return out, lineNos
+}
+
+func splitFieldsPreserveQuotes(s string) []string {
+	var out []string
+	var cur strings.Builder
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			inQuote = !inQuote
+			cur.WriteByte(c)
+		case ' ':
+			if inQuote {
+				cur.WriteByte(c)
+			} else if cur.Len() > 0 {
+				out = append(out, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if cur.Len() > 0 {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+func trimQuotes(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package routeros
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePeerAddLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantKV   map[string]string
+		wantName string
+	}{
+		{
+			name: "simple unquoted fields",
+			line: `add client-address=10.0.0.2/32 name=alice public-key=AAAA`,
+			wantKV: map[string]string{
+				"client-address": "10.0.0.2/32",
+				"name":           "alice",
+				"public-key":     "AAAA",
+			},
+			wantName: "alice",
+		},
+		{
+			name: "quoted value with spaces",
+			line: `add name="alice's laptop" client-address=10.0.0.2/32`,
+			wantKV: map[string]string{
+				"name":           "alice's laptop",
+				"client-address": "10.0.0.2/32",
+			},
+			wantName: "alice's laptop",
+		},
+		{
+			name: "empty value absorbs unquoted continuation tokens",
+			line: `add comment= multi word comment name=bob`,
+			wantKV: map[string]string{
+				"comment": "multi word comment",
+				"name":    "bob",
+			},
+			wantName: "bob",
+		},
+		{
+			name: "camelCase aliases map to canonical names",
+			line: `add clientAddress=10.0.0.3/32 clientDns=1.1.1.1 endpointAddress=vpn.example.com endpointPort=51820 allowedAddress=0.0.0.0/0`,
+			wantKV: map[string]string{
+				"clientaddress":    "10.0.0.3/32",
+				"client-address":   "10.0.0.3/32",
+				"clientdns":        "1.1.1.1",
+				"client-dns":       "1.1.1.1",
+				"endpointaddress":  "vpn.example.com",
+				"endpoint-address": "vpn.example.com",
+				"endpointport":     "51820",
+				"endpoint-port":    "51820",
+				"allowedaddress":   "0.0.0.0/0",
+				"allowed-address":  "0.0.0.0/0",
+			},
+			wantName: "",
+		},
+		{
+			name:     "nothing after add",
+			line:     `add `,
+			wantKV:   nil,
+			wantName: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv, name := parsePeerAddLine(tt.line)
+			if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+			if !reflect.DeepEqual(kv, tt.wantKV) {
+				t.Errorf("kv = %#v, want %#v", kv, tt.wantKV)
+			}
+		})
+	}
+}
+
+func TestJoinContinuations(t *testing.T) {
+	lines := []string{
+		`add name=alice \`,
+		`    client-address=10.0.0.2/32`,
+		`add name=bob client-address=10.0.0.3/32`,
+	}
+	joined, lineNos := joinContinuations(lines)
+	want := []string{
+		"add name=alice client-address=10.0.0.2/32",
+		"add name=bob client-address=10.0.0.3/32",
+	}
+	if !reflect.DeepEqual(joined, want) {
+		t.Errorf("joined = %#v, want %#v", joined, want)
+	}
+	if !reflect.DeepEqual(lineNos, []int{1, 3}) {
+		t.Errorf("lineNos = %v, want [1 3]", lineNos)
+	}
+}
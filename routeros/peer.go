@@ -0,0 +1,172 @@
+// Package routeros reads WireGuard peer configuration off a MikroTik
+// RouterOS device, either from a `/interface wireguard peers export`
+// (wg.rsc) or live from the device's API. Both sources converge on the
+// same Peer shape so the rest of the module doesn't care which one fed
+// it.
+package routeros
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// Peer is a single WireGuard peer configured on a RouterOS device.
+// RouterOS can auto-generate a client keypair for a peer, so PrivateKey
+// here is the client's own private key, not the server's.
+type Peer struct {
+	Name           string
+	ClientAddress  netip.Prefix
+	ClientDNS      []string
+	Endpoint       wgcfg.Endpoint
+	PrivateKey     wgcfg.Key
+	PublicKey      wgcfg.Key
+	PresharedKey   wgcfg.Key
+	AllowedAddress []netip.Prefix
+}
+
+// IsComplete reports whether p has everything needed to generate a
+// client bundle (address, private key, preshared key, endpoint).
+func (p Peer) IsComplete() bool {
+	return p.ClientAddress.IsValid() && !p.PrivateKey.IsZero() && !p.PresharedKey.IsZero() && !p.Endpoint.IsZero()
+}
+
+// peerFromFields builds a Peer from a flat key/value map using
+// RouterOS's `/interface wireguard peers` property names (shared by
+// both the .rsc export and the live API, which use the same names).
+// Every present field is validated; line is attached to each
+// *wgcfg.ParseError for context and is 0 when there's no source line
+// (e.g. a live API reply). The peer is rejected (nil Peer) if a
+// required field is missing or any field fails validation.
+func peerFromFields(kv map[string]string, line int) (*Peer, []*wgcfg.ParseError) {
+	p := &Peer{
+		Name: kv["name"],
+	}
+	var errs []*wgcfg.ParseError
+	fail := func(why, offender string) {
+		errs = append(errs, &wgcfg.ParseError{Why: why, Offender: offender, Line: line})
+	}
+
+	addr := firstNonEmpty(kv["client-address"], kv["address"])
+	switch {
+	case addr == "":
+		fail("missing client-address", "")
+	default:
+		prefix, err := wgcfg.ParsePrefix(addr)
+		if err != nil {
+			fail("invalid client-address", addr)
+		} else {
+			p.ClientAddress = prefix
+		}
+	}
+
+	if dns := kv["client-dns"]; dns != "" {
+		for _, part := range splitCSV(dns) {
+			if err := wgcfg.ValidateDNSEntry(part); err != nil {
+				fail("invalid client-dns entry", part)
+				continue
+			}
+			p.ClientDNS = append(p.ClientDNS, part)
+		}
+	}
+
+	endpointAddress := firstNonEmpty(kv["endpoint-address"], kv["endpoint"])
+	endpointPort := kv["endpoint-port"]
+	switch {
+	case endpointAddress == "" || endpointPort == "":
+		fail("missing endpoint", "")
+	default:
+		ep, err := wgcfg.ParseEndpoint(fmt.Sprintf("%s:%s", endpointAddress, endpointPort))
+		if err != nil {
+			fail("invalid endpoint", fmt.Sprintf("%s:%s", endpointAddress, endpointPort))
+		} else {
+			p.Endpoint = ep
+		}
+	}
+
+	switch pk := kv["private-key"]; {
+	case pk == "":
+		fail("missing private-key", "")
+	default:
+		k, err := wgcfg.ParseKey(pk)
+		if err != nil {
+			// The value is left out so the rejected-peer report does
+			// not store (malformed) private key text.
+			fail("invalid private-key", "")
+		} else {
+			p.PrivateKey = k
+		}
+	}
+
+	switch pk := kv["public-key"]; {
+	case pk != "":
+		k, err := wgcfg.ParseKey(pk)
+		if err != nil {
+			fail("invalid public-key", pk)
+		} else {
+			p.PublicKey = k
+		}
+	case !p.PrivateKey.IsZero():
+		// RouterOS doesn't always record the public half of an
+		// auto-generated client keypair; derive it so callers that
+		// key off PublicKey (e.g. wgshow reconciliation) still see a
+		// real, matchable key instead of the zero value.
+		k, err := wgcfg.PublicFromPrivate(p.PrivateKey)
+		if err != nil {
+			fail("deriving public-key", "")
+		} else {
+			p.PublicKey = k
+		}
+	}
+
+	switch psk := kv["preshared-key"]; {
+	case psk == "":
+		fail("missing preshared-key", "")
+	default:
+		k, err := wgcfg.ParseKey(psk)
+		if err != nil {
+			fail("invalid preshared-key", "")
+		} else {
+			p.PresharedKey = k
+		}
+	}
+
+	if allowed := kv["allowed-address"]; allowed != "" {
+		for _, part := range splitCSV(allowed) {
+			prefix, err := wgcfg.ParsePrefix(part)
+			if err != nil {
+				fail("invalid allowed-address entry", part)
+				continue
+			}
+			p.AllowedAddress = append(p.AllowedAddress, prefix)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return p, nil
+}
+
+func splitCSV(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
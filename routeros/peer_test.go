@@ -0,0 +1,91 @@
+package routeros
+
+import "testing"
+
+func validFields() map[string]string {
+	return map[string]string{
+		"name":             "alice",
+		"client-address":   "10.0.0.2/32",
+		"private-key":      "YAtZI0VVD1hzIpDzfHuwnaGTzhA90WtAl6fQCk9GVUM=",
+		"preshared-key":    "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=",
+		"endpoint-address": "vpn.example.com",
+		"endpoint-port":    "51820",
+	}
+}
+
+func TestPeerFromFieldsDerivesMissingPublicKey(t *testing.T) {
+	kv := validFields()
+	delete(kv, "public-key")
+
+	p, errs := peerFromFields(kv, 1)
+	if len(errs) != 0 {
+		t.Fatalf("peerFromFields: unexpected errors %v", errs)
+	}
+	if p.PublicKey.IsZero() {
+		t.Fatal("PublicKey is zero, want it derived from private-key")
+	}
+}
+
+func TestPeerFromFieldsUsesRecordedPublicKey(t *testing.T) {
+	kv := validFields()
+	kv["public-key"] = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAI="
+
+	p, errs := peerFromFields(kv, 1)
+	if len(errs) != 0 {
+		t.Fatalf("peerFromFields: unexpected errors %v", errs)
+	}
+	if p.PublicKey.String() != kv["public-key"] {
+		t.Fatalf("PublicKey = %q, want recorded %q", p.PublicKey.String(), kv["public-key"])
+	}
+}
+
+func TestPeerFromFieldsMissingRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		drop string
+	}{
+		{"missing client-address", "client-address"},
+		{"missing private-key", "private-key"},
+		{"missing preshared-key", "preshared-key"},
+		{"missing endpoint-address", "endpoint-address"},
+		{"missing endpoint-port", "endpoint-port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv := validFields()
+			delete(kv, tt.drop)
+			p, errs := peerFromFields(kv, 1)
+			if p != nil {
+				t.Fatalf("peerFromFields with %s missing: got a Peer, want nil", tt.drop)
+			}
+			if len(errs) == 0 {
+				t.Fatalf("peerFromFields with %s missing: got no errors, want at least one", tt.drop)
+			}
+		})
+	}
+}
+
+func TestPeerFromFieldsInvalidPrivateKeyDoesNotDerivePublicKey(t *testing.T) {
+	kv := validFields()
+	kv["private-key"] = "not-a-valid-key"
+	delete(kv, "public-key")
+
+	p, errs := peerFromFields(kv, 1)
+	if p != nil {
+		t.Fatal("peerFromFields with an invalid private-key: got a Peer, want nil")
+	}
+	if len(errs) != 1 {
+		t.Fatalf("peerFromFields with an invalid private-key: got %d errors, want exactly 1 (no spurious derive error)", len(errs))
+	}
+}
+
+func TestPeerIsComplete(t *testing.T) {
+	kv := validFields()
+	p, errs := peerFromFields(kv, 1)
+	if len(errs) != 0 {
+		t.Fatalf("peerFromFields: unexpected errors %v", errs)
+	}
+	if !p.IsComplete() {
+		t.Fatal("IsComplete() = false for a fully-populated peer")
+	}
+}
@@ -0,0 +1,67 @@
+package routeros
+
+import (
+	"net/netip"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// RejectedPeer records one input row that failed validation and why.
+type RejectedPeer struct {
+	Name   string              `json:"name,omitempty"`
+	Line   int                 `json:"line,omitempty"`
+	Errors []*wgcfg.ParseError `json:"errors"`
+}
+
+// Report is the result of reading a peer source: what was accepted as
+// a usable Peer, and what was rejected and why. Accepted peers carry
+// their keys, so the -report file CI pipelines check holds the
+// redacted Summary rather than the Report itself.
+type Report struct {
+	Accepted []Peer         `json:"accepted"`
+	Rejected []RejectedPeer `json:"rejected"`
+}
+
+func (r *Report) add(p *Peer, name string, line int, errs []*wgcfg.ParseError) {
+	if p != nil {
+		r.Accepted = append(r.Accepted, *p)
+		return
+	}
+	r.Rejected = append(r.Rejected, RejectedPeer{Name: name, Line: line, Errors: errs})
+}
+
+// AcceptedSummary is the non-secret subset of an accepted Peer: enough
+// to audit which peers were read and how, without the report becoming
+// another place a client's private or preshared key is stored.
+type AcceptedSummary struct {
+	Name           string         `json:"name"`
+	ClientAddress  netip.Prefix   `json:"client_address"`
+	ClientDNS      []string       `json:"client_dns,omitempty"`
+	Endpoint       wgcfg.Endpoint `json:"endpoint"`
+	AllowedAddress []netip.Prefix `json:"allowed_address,omitempty"`
+}
+
+// Summary is the redacted form of a Report safe to write to disk or
+// hand to CI: accepted peers are reduced to AcceptedSummary, dropping
+// PrivateKey/PublicKey/PresharedKey, while Rejected (which holds no
+// private or preshared key) is unchanged.
+type Summary struct {
+	Accepted []AcceptedSummary `json:"accepted"`
+	Rejected []RejectedPeer    `json:"rejected"`
+}
+
+// Summary returns the redacted view of r suitable for writing to the
+// -report file.
+func (r *Report) Summary() Summary {
+	accepted := make([]AcceptedSummary, len(r.Accepted))
+	for i, p := range r.Accepted {
+		accepted[i] = AcceptedSummary{
+			Name:           p.Name,
+			ClientAddress:  p.ClientAddress,
+			ClientDNS:      p.ClientDNS,
+			Endpoint:       p.Endpoint,
+			AllowedAddress: p.AllowedAddress,
+		}
+	}
+	return Summary{Accepted: accepted, Rejected: r.Rejected}
+}
@@ -0,0 +1,43 @@
+package routeros
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReportSummaryDropsKeyMaterial(t *testing.T) {
+	kv := validFields()
+	p, errs := peerFromFields(kv, 1)
+	if len(errs) != 0 {
+		t.Fatalf("peerFromFields: unexpected errors %v", errs)
+	}
+
+	r := &Report{}
+	r.add(p, p.Name, 1, nil)
+	r.add(nil, "bob", 2, nil)
+
+	summary := r.Summary()
+	if len(summary.Accepted) != 1 {
+		t.Fatalf("len(Accepted) = %d, want 1", len(summary.Accepted))
+	}
+	if len(summary.Rejected) != 1 {
+		t.Fatalf("len(Rejected) = %d, want 1", len(summary.Rejected))
+	}
+	if summary.Accepted[0].Name != p.Name {
+		t.Errorf("Name = %q, want %q", summary.Accepted[0].Name, p.Name)
+	}
+	if summary.Accepted[0].ClientAddress != p.ClientAddress {
+		t.Errorf("ClientAddress = %v, want %v", summary.Accepted[0].ClientAddress, p.ClientAddress)
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("marshal summary: %v", err)
+	}
+	for _, secret := range []string{p.PrivateKey.String(), p.PresharedKey.String(), p.PublicKey.String()} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Summary JSON contains key material %q", secret)
+		}
+	}
+}
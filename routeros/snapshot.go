@@ -0,0 +1,36 @@
+package routeros
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// LoadSnapshot reads a previously saved peer snapshot. A missing file
+// is not an error: it returns a nil slice, treating every current peer
+// as new.
+func LoadSnapshot(path string) ([]Peer, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var peers []Peer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// SaveSnapshot writes the current peers to path for a future Diff.
+// Unlike the -report file, the snapshot must retain full key
+// material (Diff compares it field-for-field), so it's written 0600
+// instead of being redacted.
+func SaveSnapshot(path string, peers []Peer) error {
+	data, err := json.MarshalIndent(peers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,42 @@
+package routeros
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wg.snapshot.json")
+	peers := []Peer{
+		{Name: "alice", PublicKey: mustTestKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")},
+		{Name: "bob", ClientAddress: mustTestPrefix(t, "10.0.0.3/32")},
+	}
+
+	if err := SaveSnapshot(path, peers); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	got, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if len(got) != len(peers) {
+		t.Fatalf("LoadSnapshot returned %d peers, want %d", len(got), len(peers))
+	}
+	if got[0].Name != "alice" || got[0].PublicKey != peers[0].PublicKey {
+		t.Errorf("got[0] = %+v, want %+v", got[0], peers[0])
+	}
+	if got[1].Name != "bob" || got[1].ClientAddress != peers[1].ClientAddress {
+		t.Errorf("got[1] = %+v, want %+v", got[1], peers[1])
+	}
+}
+
+func TestLoadSnapshotMissingFileIsNotAnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.json")
+	peers, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot of a missing file: %v, want nil error", err)
+	}
+	if peers != nil {
+		t.Errorf("LoadSnapshot of a missing file returned %v, want nil", peers)
+	}
+}
@@ -0,0 +1,114 @@
+// Package wgcfg provides typed models for WireGuard interface and peer
+// configuration, plus a Parse/Marshal pair for the standard wg-quick
+// ini format. It plays the same role here that the conf package plays
+// in wireguard-windows: a small core, depending only on the standard
+// library and golang.org/x/crypto/curve25519, that the rest of
+// the module builds on (RouterOS ingestion, client-bundle generation,
+// validation).
+package wgcfg
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a peer's host:port, kept split so the host can be a bare
+// IPv4/IPv6 address or a DNS name without ambiguity around ":" in IPv6
+// literals.
+type Endpoint struct {
+	Host string
+	Port uint16
+}
+
+// String renders the endpoint as WireGuard expects it on the wire,
+// bracketing IPv6 literal hosts.
+func (e Endpoint) String() string {
+	if e.Host == "" {
+		return ""
+	}
+	if strings.Contains(e.Host, ":") {
+		return fmt.Sprintf("[%s]:%d", e.Host, e.Port)
+	}
+	return fmt.Sprintf("%s:%d", e.Host, e.Port)
+}
+
+// IsZero reports whether e has no host set.
+func (e Endpoint) IsZero() bool {
+	return e.Host == ""
+}
+
+// ParseEndpoint parses a "host:port" or "[host]:port" string.
+func ParseEndpoint(s string) (Endpoint, error) {
+	var e Endpoint
+	if s == "" {
+		return e, &ParseError{Why: "empty endpoint", Offender: s}
+	}
+	host, portStr, err := splitHostPort(s)
+	if err != nil {
+		return e, &ParseError{Why: err.Error(), Offender: s}
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return e, &ParseError{Why: "endpoint has a non-numeric port", Offender: s}
+	}
+	if err := ValidatePort(port); err != nil {
+		return e, &ParseError{Why: err.(*ParseError).Why, Offender: s}
+	}
+	e.Host = host
+	e.Port = uint16(port)
+	return e, nil
+}
+
+// splitHostPort is net.SplitHostPort with bracket handling relaxed
+// enough to accept the host forms RouterOS and wg-quick both emit.
+func splitHostPort(s string) (host, port string, err error) {
+	if strings.HasPrefix(s, "[") {
+		i := strings.Index(s, "]")
+		if i < 0 {
+			return "", "", fmt.Errorf("missing ']' in %q", s)
+		}
+		host = s[1:i]
+		rest := s[i+1:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", fmt.Errorf("missing port after ']' in %q", s)
+		}
+		return host, rest[1:], nil
+	}
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("missing port in %q", s)
+	}
+	return s[:i], s[i+1:], nil
+}
+
+// Interface is the local [Interface] section of a WireGuard config:
+// this host's own identity and network settings.
+type Interface struct {
+	PrivateKey Key
+	ListenPort uint16
+	Address    []netip.Prefix
+	// DNS entries are kept as validated strings rather than netip.Addr
+	// because WireGuard configs allow plain domain names here as well
+	// as IPs.
+	DNS []string
+	MTU uint16
+}
+
+// Peer is a single [Peer] section: a remote endpoint this interface
+// talks to.
+type Peer struct {
+	PublicKey           Key
+	PresharedKey        Key
+	AllowedIPs          []netip.Prefix
+	Endpoint            Endpoint
+	PersistentKeepalive uint16
+}
+
+// Config is a full WireGuard configuration: one interface and the
+// peers it talks to.
+type Config struct {
+	Interface Interface
+	Peers     []Peer
+}
@@ -0,0 +1,20 @@
+package wgcfg
+
+import "fmt"
+
+// ParseError describes one invalid field encountered while parsing a
+// config or peer record: what was wrong (Why), the value that
+// triggered it (Offender), and the source line it came from, when
+// known (Line is 0 when there's no meaningful line, e.g. an API reply).
+type ParseError struct {
+	Why      string
+	Offender string
+	Line     int
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %q", e.Line, e.Why, e.Offender)
+	}
+	return fmt.Sprintf("%s: %q", e.Why, e.Offender)
+}
@@ -0,0 +1,73 @@
+package wgcfg
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// KeyLength is the length in bytes of a WireGuard public, private, or
+// preshared key.
+const KeyLength = 32
+
+// Key is a fixed-length WireGuard key (public, private, or preshared),
+// stored in its raw binary form. Configs encode keys as base64.
+type Key [KeyLength]byte
+
+// ParseKey decodes a standard-base64-encoded WireGuard key, returning a
+// *ParseError if s does not decode to exactly KeyLength bytes.
+func ParseKey(s string) (Key, error) {
+	var k Key
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return k, &ParseError{Why: "invalid key", Offender: s}
+	}
+	if len(decoded) != KeyLength {
+		return k, &ParseError{Why: fmt.Sprintf("invalid key: decoded to %d bytes, want %d", len(decoded), KeyLength), Offender: s}
+	}
+	copy(k[:], decoded)
+	return k, nil
+}
+
+// String returns the standard base64 encoding of k, as used in WireGuard
+// configs and `wg` output.
+func (k Key) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// IsZero reports whether k is the all-zero key, i.e. it was never set.
+func (k Key) IsZero() bool {
+	var zero Key
+	return k == zero
+}
+
+// MarshalText implements encoding.TextMarshaler so a Key round-trips as
+// its base64 form in JSON and other text-based encodings.
+func (k Key) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *Key) UnmarshalText(text []byte) error {
+	parsed, err := ParseKey(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+	return nil
+}
+
+// PublicFromPrivate derives the Curve25519 public key for a WireGuard
+// private key, so a peer's public key can be recovered when only its
+// private key is known (e.g. a RouterOS export that auto-generated a
+// client keypair but only recorded the private half).
+func PublicFromPrivate(priv Key) (Key, error) {
+	var pub Key
+	out, err := curve25519.X25519(priv[:], curve25519.Basepoint)
+	if err != nil {
+		return pub, fmt.Errorf("deriving public key: %w", err)
+	}
+	copy(pub[:], out)
+	return pub, nil
+}
@@ -0,0 +1,65 @@
+package wgcfg
+
+import "testing"
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"valid key", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", false},
+		{"not base64", "not-valid-base64!!", true},
+		{"decodes too short", "AAAA", true},
+		{"empty string", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKey(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKey(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && k.String() != tt.in {
+				t.Fatalf("ParseKey(%q).String() = %q, want %q", tt.in, k.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestKeyIsZero(t *testing.T) {
+	var zero Key
+	if !zero.IsZero() {
+		t.Fatal("zero-value Key.IsZero() = false, want true")
+	}
+	nonZero, err := ParseKey("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	if nonZero.IsZero() {
+		t.Fatal("non-zero Key.IsZero() = true, want false")
+	}
+}
+
+func TestPublicFromPrivate(t *testing.T) {
+	// A fixed, valid Curve25519 private key (clamped), so the derived
+	// public key is deterministic.
+	priv, err := ParseKey("YAtZI0VVD1hzIpDzfHuwnaGTzhA90WtAl6fQCk9GVUM=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	pub, err := PublicFromPrivate(priv)
+	if err != nil {
+		t.Fatalf("PublicFromPrivate: %v", err)
+	}
+	if pub.IsZero() {
+		t.Fatal("PublicFromPrivate returned the zero key")
+	}
+	// Deriving twice from the same private key must be deterministic.
+	pub2, err := PublicFromPrivate(priv)
+	if err != nil {
+		t.Fatalf("PublicFromPrivate (second call): %v", err)
+	}
+	if pub != pub2 {
+		t.Fatalf("PublicFromPrivate is not deterministic: %v != %v", pub, pub2)
+	}
+}
@@ -0,0 +1,57 @@
+package wgcfg
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Marshal renders cfg as a standard wg-quick style ini config.
+func Marshal(cfg *Config) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString("[Interface]\n")
+	if !cfg.Interface.PrivateKey.IsZero() {
+		fmt.Fprintf(&b, "PrivateKey = %s\n", cfg.Interface.PrivateKey)
+	}
+	if cfg.Interface.ListenPort != 0 {
+		fmt.Fprintf(&b, "ListenPort = %d\n", cfg.Interface.ListenPort)
+	}
+	if len(cfg.Interface.Address) > 0 {
+		fmt.Fprintf(&b, "Address = %s\n", joinPrefixes(cfg.Interface.Address))
+	}
+	if len(cfg.Interface.DNS) > 0 {
+		fmt.Fprintf(&b, "DNS = %s\n", strings.Join(cfg.Interface.DNS, ","))
+	}
+	if cfg.Interface.MTU != 0 {
+		fmt.Fprintf(&b, "MTU = %s\n", strconv.Itoa(int(cfg.Interface.MTU)))
+	}
+
+	for _, p := range cfg.Peers {
+		b.WriteString("\n[Peer]\n")
+		fmt.Fprintf(&b, "PublicKey = %s\n", p.PublicKey)
+		if !p.PresharedKey.IsZero() {
+			fmt.Fprintf(&b, "PresharedKey = %s\n", p.PresharedKey)
+		}
+		if len(p.AllowedIPs) > 0 {
+			fmt.Fprintf(&b, "AllowedIPs = %s\n", joinPrefixes(p.AllowedIPs))
+		}
+		if !p.Endpoint.IsZero() {
+			fmt.Fprintf(&b, "Endpoint = %s\n", p.Endpoint)
+		}
+		if p.PersistentKeepalive != 0 {
+			fmt.Fprintf(&b, "PersistentKeepalive = %d\n", p.PersistentKeepalive)
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+func joinPrefixes(prefixes []netip.Prefix) string {
+	parts := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, ",")
+}
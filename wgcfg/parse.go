@@ -0,0 +1,201 @@
+package wgcfg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a standard wg-quick style ini config (one [Interface]
+// section, zero or more [Peer] sections) and returns the typed Config.
+// Any invalid field is reported as a *ParseError carrying the source
+// line.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	var curPeer *Peer
+	inInterface := false
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") {
+			switch {
+			case strings.EqualFold(text, "[Interface]"):
+				inInterface = true
+				curPeer = nil
+			case strings.EqualFold(text, "[Peer]"):
+				inInterface = false
+				cfg.Peers = append(cfg.Peers, Peer{})
+				curPeer = &cfg.Peers[len(cfg.Peers)-1]
+			default:
+				return nil, &ParseError{Why: "unknown section", Offender: text, Line: line}
+			}
+			continue
+		}
+
+		key, val, ok := splitKV(text)
+		if !ok {
+			return nil, &ParseError{Why: "expected key = value", Offender: text, Line: line}
+		}
+
+		var err error
+		switch {
+		case inInterface:
+			err = setInterfaceField(&cfg.Interface, key, val)
+		case curPeer != nil:
+			err = setPeerField(curPeer, key, val)
+		default:
+			err = &ParseError{Why: "key outside of any section", Offender: key}
+		}
+		if perr, ok := err.(*ParseError); ok {
+			perr.Line = line
+			return nil, perr
+		} else if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func splitKV(line string) (key, val string, ok bool) {
+	i := strings.IndexByte(line, '=')
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+func setInterfaceField(iface *Interface, key, val string) error {
+	switch strings.ToLower(key) {
+	case "privatekey":
+		k, err := ParseKey(val)
+		if err != nil {
+			return err
+		}
+		iface.PrivateKey = k
+	case "listenport":
+		port, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return &ParseError{Why: "invalid ListenPort", Offender: val}
+		}
+		if err := ValidatePort(port); err != nil {
+			return err
+		}
+		iface.ListenPort = uint16(port)
+	case "address":
+		prefixes, err := parsePrefixList(val)
+		if err != nil {
+			return err
+		}
+		iface.Address = append(iface.Address, prefixes...)
+	case "dns":
+		for _, part := range splitCSV(val) {
+			if err := ValidateDNSEntry(part); err != nil {
+				return err
+			}
+			iface.DNS = append(iface.DNS, part)
+		}
+	case "mtu":
+		mtu, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return &ParseError{Why: "invalid MTU", Offender: val}
+		}
+		iface.MTU = uint16(mtu)
+	default:
+		// Unknown interface keys (Table, PostUp, ...) are ignored;
+		// this module only cares about the fields above.
+	}
+	return nil
+}
+
+func setPeerField(peer *Peer, key, val string) error {
+	switch strings.ToLower(key) {
+	case "publickey":
+		k, err := ParseKey(val)
+		if err != nil {
+			return err
+		}
+		peer.PublicKey = k
+	case "presharedkey":
+		k, err := ParseKey(val)
+		if err != nil {
+			return err
+		}
+		peer.PresharedKey = k
+	case "allowedips":
+		prefixes, err := parsePrefixList(val)
+		if err != nil {
+			return err
+		}
+		peer.AllowedIPs = append(peer.AllowedIPs, prefixes...)
+	case "endpoint":
+		ep, err := ParseEndpoint(val)
+		if err != nil {
+			return err
+		}
+		peer.Endpoint = ep
+	case "persistentkeepalive":
+		ka, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return &ParseError{Why: "invalid PersistentKeepalive", Offender: val}
+		}
+		peer.PersistentKeepalive = uint16(ka)
+	default:
+		// Unknown peer keys are ignored.
+	}
+	return nil
+}
+
+func splitCSV(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// parsePrefixList parses a comma-separated list of CIDRs or bare IPs,
+// upgrading bare IPs to host prefixes (/32 or /128).
+func parsePrefixList(s string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, part := range splitCSV(s) {
+		prefix, err := ParsePrefix(part)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, prefix)
+	}
+	return out, nil
+}
+
+// ParsePrefix parses a CIDR, upgrading a bare IP to a host prefix
+// (/32 for IPv4, /128 for IPv6).
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, &ParseError{Why: "invalid address", Offender: s}
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, &ParseError{Why: "invalid prefix", Offender: s}
+	}
+	return prefix, nil
+}
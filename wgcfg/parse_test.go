@@ -0,0 +1,125 @@
+package wgcfg
+
+import "testing"
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"bare IPv4 upgraded to /32", "10.0.0.1", "10.0.0.1/32", false},
+		{"bare IPv6 upgraded to /128", "fd00::1", "fd00::1/128", false},
+		{"IPv4 CIDR", "10.0.0.0/24", "10.0.0.0/24", false},
+		{"IPv6 CIDR", "fd00::/64", "fd00::/64", false},
+		{"invalid address", "not-an-ip", "", true},
+		{"invalid CIDR", "10.0.0.0/99", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePrefix(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrefix(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Fatalf("ParsePrefix(%q) = %q, want %q", tt.in, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantStr string
+		wantErr bool
+	}{
+		{"IPv4 host:port", "10.0.0.1:51820", "10.0.0.1:51820", false},
+		{"DNS host:port", "vpn.example.com:51820", "vpn.example.com:51820", false},
+		{"bracketed IPv6 host:port", "[fd00::1]:51820", "[fd00::1]:51820", false},
+		{"missing port", "10.0.0.1", "", true},
+		{"non-numeric port", "10.0.0.1:abc", "", true},
+		{"port zero is invalid", "10.0.0.1:0", "", true},
+		{"unterminated bracket", "[fd00::1:51820", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEndpoint(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEndpoint(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.wantStr {
+				t.Fatalf("ParseEndpoint(%q).String() = %q, want %q", tt.in, got.String(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	const conf = `[Interface]
+PrivateKey = AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=
+Address = 10.0.0.2/32
+ListenPort = 51820
+DNS = 1.1.1.1
+
+[Peer]
+PublicKey = AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=
+AllowedIPs = 0.0.0.0/0
+Endpoint = vpn.example.com:51820
+PersistentKeepalive = 25
+`
+	cfg, err := Parse([]byte(conf))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cfg.Interface.ListenPort != 51820 {
+		t.Errorf("ListenPort = %d, want 51820", cfg.Interface.ListenPort)
+	}
+	if len(cfg.Interface.Address) != 1 || cfg.Interface.Address[0].String() != "10.0.0.2/32" {
+		t.Errorf("Address = %v, want [10.0.0.2/32]", cfg.Interface.Address)
+	}
+	if len(cfg.Peers) != 1 {
+		t.Fatalf("len(Peers) = %d, want 1", len(cfg.Peers))
+	}
+	peer := cfg.Peers[0]
+	if peer.Endpoint.String() != "vpn.example.com:51820" {
+		t.Errorf("Peer.Endpoint = %q, want %q", peer.Endpoint.String(), "vpn.example.com:51820")
+	}
+	if peer.PersistentKeepalive != 25 {
+		t.Errorf("PersistentKeepalive = %d, want 25", peer.PersistentKeepalive)
+	}
+}
+
+func TestParseRejectsUnknownSection(t *testing.T) {
+	_, err := Parse([]byte("[Bogus]\nfoo = bar\n"))
+	if err == nil {
+		t.Fatal("Parse with an unknown section: got nil error, want one")
+	}
+}
+
+func TestParseRejectsKeyOutsideSection(t *testing.T) {
+	_, err := Parse([]byte("foo = bar\n"))
+	if err == nil {
+		t.Fatal("Parse with a key outside any section: got nil error, want one")
+	}
+}
+
+func TestParseInvalidKeyFieldReturnsParseError(t *testing.T) {
+	const conf = `[Interface]
+PrivateKey = not-a-valid-key
+`
+	_, err := Parse([]byte(conf))
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Parse error type = %T, want *ParseError", err)
+	}
+	if perr.Offender != "not-a-valid-key" {
+		t.Errorf("Offender = %q, want %q", perr.Offender, "not-a-valid-key")
+	}
+	if perr.Line != 2 {
+		t.Errorf("Line = %d, want 2", perr.Line)
+	}
+}
@@ -0,0 +1,35 @@
+package wgcfg
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+)
+
+// hostnameRE matches a syntactically valid DNS domain name (labels of
+// letters, digits and hyphens, not starting or ending with a hyphen).
+var hostnameRE = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// ValidateDNSEntry reports whether s is usable as a DNS server entry:
+// either a valid IP address or a syntactically valid domain name.
+func ValidateDNSEntry(s string) error {
+	if s == "" {
+		return &ParseError{Why: "empty DNS entry", Offender: s}
+	}
+	if _, err := netip.ParseAddr(s); err == nil {
+		return nil
+	}
+	if len(s) <= 253 && hostnameRE.MatchString(s) {
+		return nil
+	}
+	return &ParseError{Why: "DNS entry is not an IP address or valid domain name", Offender: s}
+}
+
+// ValidatePort reports whether port is a valid WireGuard/UDP port
+// number; 0 is reserved and not a usable listen or endpoint port.
+func ValidatePort(port uint64) error {
+	if port < 1 || port > 65535 {
+		return &ParseError{Why: "port must be 1-65535", Offender: fmt.Sprintf("%d", port)}
+	}
+	return nil
+}
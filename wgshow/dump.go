@@ -0,0 +1,159 @@
+// Package wgshow parses `wg show <iface> dump` output so the peers a
+// running server actually has can be reconciled against what wg.rsc
+// says should exist.
+package wgshow
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// Interface is the first line of `wg show <iface> dump`: the running
+// server's own keys, listen port, and fwmark.
+type Interface struct {
+	PrivateKey   wgcfg.Key
+	PublicKey    wgcfg.Key
+	ListenPort   uint16
+	FirewallMark string
+}
+
+// Peer is one peer line of `wg show <iface> dump`.
+type Peer struct {
+	PublicKey           wgcfg.Key
+	PresharedKey        wgcfg.Key // zero if the peer has none
+	Endpoint            string    // host:port as last seen by the kernel; empty if never connected
+	AllowedIPs          []string
+	LatestHandshake     time.Time // zero if never handshaken
+	ReceiveBytes        uint64
+	TransmitBytes       uint64
+	PersistentKeepalive int // seconds; 0 if off
+}
+
+// Dump runs `wg show <iface> dump` and parses its output.
+func Dump(iface string) (*Interface, []Peer, error) {
+	out, err := exec.Command("wg", "show", iface, "dump").Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("wg show %s dump: %w", iface, err)
+	}
+	return Parse(strings.NewReader(string(out)))
+}
+
+// Parse reads `wg show <iface> dump` output — whether piped from Dump
+// or captured to a file for offline reconciliation.
+func Parse(r io.Reader) (*Interface, []Peer, error) {
+	scanner := bufio.NewScanner(r)
+	var iface *Interface
+	var peers []Peer
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r\n")
+		if text == "" {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if iface == nil {
+			i, err := parseInterfaceLine(fields)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			iface = i
+			continue
+		}
+		p, err := parsePeerLine(fields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		peers = append(peers, *p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if iface == nil {
+		return nil, nil, fmt.Errorf("empty wg show dump")
+	}
+	return iface, peers, nil
+}
+
+func parseInterfaceLine(f []string) (*Interface, error) {
+	if len(f) != 4 {
+		return nil, fmt.Errorf("want 4 fields in the interface line, got %d", len(f))
+	}
+	priv, err := wgcfg.ParseKey(f[0])
+	if err != nil {
+		return nil, fmt.Errorf("private-key: %w", err)
+	}
+	pub, err := wgcfg.ParseKey(f[1])
+	if err != nil {
+		return nil, fmt.Errorf("public-key: %w", err)
+	}
+	port, err := strconv.ParseUint(f[2], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("listen-port: %w", err)
+	}
+	return &Interface{PrivateKey: priv, PublicKey: pub, ListenPort: uint16(port), FirewallMark: f[3]}, nil
+}
+
+func parsePeerLine(f []string) (*Peer, error) {
+	if len(f) != 8 {
+		return nil, fmt.Errorf("want 8 fields in a peer line, got %d", len(f))
+	}
+	pub, err := wgcfg.ParseKey(f[0])
+	if err != nil {
+		return nil, fmt.Errorf("public-key: %w", err)
+	}
+	p := &Peer{PublicKey: pub}
+
+	if f[1] != "(none)" {
+		psk, err := wgcfg.ParseKey(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("preshared-key: %w", err)
+		}
+		p.PresharedKey = psk
+	}
+
+	if f[2] != "(none)" {
+		p.Endpoint = f[2]
+	}
+
+	if f[3] != "(none)" && f[3] != "" {
+		p.AllowedIPs = strings.Split(f[3], ",")
+	}
+
+	hs, err := strconv.ParseInt(f[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("latest-handshake: %w", err)
+	}
+	if hs > 0 {
+		p.LatestHandshake = time.Unix(hs, 0)
+	}
+
+	rx, err := strconv.ParseUint(f[5], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("transfer-rx: %w", err)
+	}
+	p.ReceiveBytes = rx
+
+	tx, err := strconv.ParseUint(f[6], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("transfer-tx: %w", err)
+	}
+	p.TransmitBytes = tx
+
+	if f[7] != "off" && f[7] != "(none)" {
+		ka, err := strconv.Atoi(f[7])
+		if err != nil {
+			return nil, fmt.Errorf("persistent-keepalive: %w", err)
+		}
+		p.PersistentKeepalive = ka
+	}
+
+	return p, nil
+}
@@ -0,0 +1,105 @@
+package wgshow
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testIfacePriv = "YAtZI0VVD1hzIpDzfHuwnaGTzhA90WtAl6fQCk9GVUM="
+const testIfacePub = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE="
+const testPeerPub = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAI="
+const testPeerPSK = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM="
+
+func TestParseInterfaceAndPeerLine(t *testing.T) {
+	dump := strings.Join([]string{
+		strings.Join([]string{testIfacePriv, testIfacePub, "51820", "off"}, "\t"),
+		strings.Join([]string{testPeerPub, testPeerPSK, "10.0.0.5:51820", "10.0.0.2/32,10.0.0.3/32", "1700000000", "100", "200", "25"}, "\t"),
+	}, "\n")
+
+	iface, peers, err := Parse(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if iface.PrivateKey.String() != testIfacePriv {
+		t.Errorf("Interface.PrivateKey = %q, want %q", iface.PrivateKey.String(), testIfacePriv)
+	}
+	if iface.ListenPort != 51820 {
+		t.Errorf("Interface.ListenPort = %d, want 51820", iface.ListenPort)
+	}
+	if iface.FirewallMark != "off" {
+		t.Errorf("Interface.FirewallMark = %q, want %q", iface.FirewallMark, "off")
+	}
+
+	if len(peers) != 1 {
+		t.Fatalf("len(peers) = %d, want 1", len(peers))
+	}
+	p := peers[0]
+	if p.PublicKey.String() != testPeerPub {
+		t.Errorf("Peer.PublicKey = %q, want %q", p.PublicKey.String(), testPeerPub)
+	}
+	if p.PresharedKey.String() != testPeerPSK {
+		t.Errorf("Peer.PresharedKey = %q, want %q", p.PresharedKey.String(), testPeerPSK)
+	}
+	if p.Endpoint != "10.0.0.5:51820" {
+		t.Errorf("Peer.Endpoint = %q, want %q", p.Endpoint, "10.0.0.5:51820")
+	}
+	if len(p.AllowedIPs) != 2 || p.AllowedIPs[0] != "10.0.0.2/32" {
+		t.Errorf("Peer.AllowedIPs = %v, want [10.0.0.2/32 10.0.0.3/32]", p.AllowedIPs)
+	}
+	if !p.LatestHandshake.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Peer.LatestHandshake = %v, want %v", p.LatestHandshake, time.Unix(1700000000, 0))
+	}
+	if p.ReceiveBytes != 100 || p.TransmitBytes != 200 {
+		t.Errorf("Peer transfer = rx %d tx %d, want rx 100 tx 200", p.ReceiveBytes, p.TransmitBytes)
+	}
+	if p.PersistentKeepalive != 25 {
+		t.Errorf("Peer.PersistentKeepalive = %d, want 25", p.PersistentKeepalive)
+	}
+}
+
+func TestParsePeerLineSentinels(t *testing.T) {
+	dump := strings.Join([]string{
+		strings.Join([]string{testIfacePriv, testIfacePub, "51820", "off"}, "\t"),
+		strings.Join([]string{testPeerPub, "(none)", "(none)", "(none)", "0", "0", "0", "off"}, "\t"),
+	}, "\n")
+
+	_, peers, err := Parse(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(peers) != 1 {
+		t.Fatalf("len(peers) = %d, want 1", len(peers))
+	}
+	p := peers[0]
+	if !p.PresharedKey.IsZero() {
+		t.Errorf("PresharedKey = %v, want zero for (none)", p.PresharedKey)
+	}
+	if p.Endpoint != "" {
+		t.Errorf("Endpoint = %q, want empty for (none)", p.Endpoint)
+	}
+	if p.AllowedIPs != nil {
+		t.Errorf("AllowedIPs = %v, want nil for (none)", p.AllowedIPs)
+	}
+	if !p.LatestHandshake.IsZero() {
+		t.Errorf("LatestHandshake = %v, want zero for 0", p.LatestHandshake)
+	}
+	if p.PersistentKeepalive != 0 {
+		t.Errorf("PersistentKeepalive = %d, want 0 for off", p.PersistentKeepalive)
+	}
+}
+
+func TestParseRejectsWrongFieldCount(t *testing.T) {
+	dump := strings.Join([]string{testIfacePriv, testIfacePub, "51820"}, "\t")
+	_, _, err := Parse(strings.NewReader(dump))
+	if err == nil {
+		t.Fatal("Parse with a short interface line: got nil error, want one")
+	}
+}
+
+func TestParseEmptyDump(t *testing.T) {
+	_, _, err := Parse(strings.NewReader(""))
+	if err == nil {
+		t.Fatal("Parse of empty input: got nil error, want one")
+	}
+}
@@ -0,0 +1,49 @@
+package wgshow
+
+import (
+	"time"
+
+	"github.com/werbes/MktWireguardExporter/routeros"
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+// Reconciliation is the result of comparing wg.rsc's intended peers
+// against what a running server's `wg show dump` actually has.
+type Reconciliation struct {
+	// NeverHandshaken are peers present on the server that have never
+	// completed a handshake.
+	NeverHandshaken []wgcfg.Key `json:"never_handshaken"`
+	// MissingFromRSC are peers the server has that wg.rsc no longer
+	// lists — stragglers an operator forgot to remove on the router.
+	MissingFromRSC []wgcfg.Key `json:"missing_from_rsc"`
+	// Stale are peers whose last handshake is older than the -stale
+	// threshold, likely dead clients worth pruning.
+	Stale []wgcfg.Key `json:"stale"`
+}
+
+// Reconcile compares the peers wg.rsc says should exist against dump,
+// a `wg show <iface> dump` snapshot of what a running server actually
+// has, flagging peers never handshaken, peers missing from wg.rsc, and
+// peers stale beyond the given threshold.
+func Reconcile(rsc []routeros.Peer, dump []Peer, stale time.Duration) Reconciliation {
+	rscKeys := make(map[wgcfg.Key]bool, len(rsc))
+	for _, p := range rsc {
+		rscKeys[p.PublicKey] = true
+	}
+
+	var rec Reconciliation
+	now := time.Now()
+	for _, d := range dump {
+		if !rscKeys[d.PublicKey] {
+			rec.MissingFromRSC = append(rec.MissingFromRSC, d.PublicKey)
+		}
+		if d.LatestHandshake.IsZero() {
+			rec.NeverHandshaken = append(rec.NeverHandshaken, d.PublicKey)
+			continue
+		}
+		if now.Sub(d.LatestHandshake) > stale {
+			rec.Stale = append(rec.Stale, d.PublicKey)
+		}
+	}
+	return rec
+}
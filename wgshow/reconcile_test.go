@@ -0,0 +1,61 @@
+package wgshow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/werbes/MktWireguardExporter/routeros"
+	"github.com/werbes/MktWireguardExporter/wgcfg"
+)
+
+func mustKey(t *testing.T, s string) wgcfg.Key {
+	t.Helper()
+	k, err := wgcfg.ParseKey(s)
+	if err != nil {
+		t.Fatalf("ParseKey(%q): %v", s, err)
+	}
+	return k
+}
+
+func TestReconcile(t *testing.T) {
+	known := mustKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")
+	missing := mustKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAI=")
+	stale := mustKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM=")
+
+	rsc := []routeros.Peer{{PublicKey: known}, {PublicKey: stale}}
+	dump := []Peer{
+		{PublicKey: known, LatestHandshake: time.Now()},
+		{PublicKey: missing, LatestHandshake: time.Now()},
+		{PublicKey: stale, LatestHandshake: time.Now().Add(-100 * time.Hour)},
+	}
+
+	rec := Reconcile(rsc, dump, 72*time.Hour)
+
+	if len(rec.MissingFromRSC) != 1 || rec.MissingFromRSC[0] != missing {
+		t.Errorf("MissingFromRSC = %v, want [%v]", rec.MissingFromRSC, missing)
+	}
+	if len(rec.Stale) != 1 || rec.Stale[0] != stale {
+		t.Errorf("Stale = %v, want [%v]", rec.Stale, stale)
+	}
+	if len(rec.NeverHandshaken) != 0 {
+		t.Errorf("NeverHandshaken = %v, want none", rec.NeverHandshaken)
+	}
+}
+
+func TestReconcileNeverHandshaken(t *testing.T) {
+	known := mustKey(t, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE=")
+	rsc := []routeros.Peer{{PublicKey: known}}
+	dump := []Peer{{PublicKey: known}} // zero LatestHandshake
+
+	rec := Reconcile(rsc, dump, 72*time.Hour)
+
+	if len(rec.NeverHandshaken) != 1 || rec.NeverHandshaken[0] != known {
+		t.Errorf("NeverHandshaken = %v, want [%v]", rec.NeverHandshaken, known)
+	}
+	if len(rec.MissingFromRSC) != 0 {
+		t.Errorf("MissingFromRSC = %v, want none", rec.MissingFromRSC)
+	}
+	if len(rec.Stale) != 0 {
+		t.Errorf("Stale = %v, want none", rec.Stale)
+	}
+}